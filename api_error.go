@@ -0,0 +1,146 @@
+package themekit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// APIError is a structured representation of a failed Shopify API response,
+// carrying enough detail for callers to make programmatic decisions via
+// errors.As instead of parsing free-text messages.
+type APIError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Field      string
+	Code       string
+	Body       []byte
+}
+
+func (e APIError) Error() string {
+	if len(e.Field) > 0 {
+		return fmt.Sprintf("%d %s: %s %s", e.StatusCode, e.Code, e.Field, string(e.Body))
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, string(e.Body))
+}
+
+// shopifyErrorEnvelope matches the common shapes of Shopify's JSON error
+// bodies: {"errors": "message"} and {"errors": {"field": ["message", ...]}}.
+type shopifyErrorEnvelope struct {
+	Errors json.RawMessage `json:"errors"`
+}
+
+// NewAPIError builds an APIError from a response, parsing Shopify's JSON
+// error envelope and rate-limit headers when present.
+func NewAPIError(resp *http.Response, body []byte) APIError {
+	apiErr := APIError{StatusCode: resp.StatusCode, Body: body, Code: codeForStatus(resp.StatusCode)}
+	apiErr.RetryAfter = retryAfter(resp)
+
+	var envelope shopifyErrorEnvelope
+	if err := json.Unmarshal(body, &envelope); err == nil && len(envelope.Errors) > 0 {
+		var fieldErrors map[string][]string
+		if err := json.Unmarshal(envelope.Errors, &fieldErrors); err == nil {
+			for field := range fieldErrors {
+				apiErr.Field = field
+				break
+			}
+		}
+	}
+
+	return apiErr
+}
+
+func codeForStatus(statusCode int) string {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	case statusCode == http.StatusNotFound:
+		return "not_found"
+	case statusCode == http.StatusUnprocessableEntity:
+		return "invalid_asset"
+	case statusCode >= 500:
+		return "server_error"
+	default:
+		return "unknown"
+	}
+}
+
+// retryAfter reads the Retry-After header (seconds) Shopify sends with
+// 429s, falling back to zero when absent or unparsable.
+func retryAfter(resp *http.Response) time.Duration {
+	header := resp.Header.Get("Retry-After")
+	if len(header) == 0 {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(header, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// shopAPICallLimitDelay reads the X-Shopify-Shop-Api-Call-Limit header
+// (e.g. "39/40") Shopify sends on every asset API response and, when the
+// bucket is full (used has reached limit), returns a delay the retry policy can fold
+// into its backoff so the next request doesn't immediately trip the limit
+// again, the same leaky-bucket accounting LeakyBucket already does for our
+// own outgoing rate.
+func shopAPICallLimitDelay(resp *http.Response, refillRate float64) time.Duration {
+	header := resp.Header.Get("X-Shopify-Shop-Api-Call-Limit")
+	if len(header) == 0 {
+		return 0
+	}
+	var used, limit int
+	if _, err := fmt.Sscanf(header, "%d/%d", &used, &limit); err != nil || limit == 0 {
+		return 0
+	}
+	if used < limit {
+		return 0
+	}
+	if refillRate <= 0 {
+		return time.Second
+	}
+	return time.Duration(float64(time.Second) / refillRate)
+}
+
+// RetryPolicy governs how Perform, CreateTheme, and Asset back off and
+// retry transient failures instead of surfacing them as immediate
+// NonFatalNetworkErrors.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	Jitter      time.Duration
+	ShouldRetry func(statusCode int) bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts (the first plus 2 retries) on
+// 429s and 5xxs, with a small linear backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		Jitter:      250 * time.Millisecond,
+		ShouldRetry: func(statusCode int) bool {
+			return statusCode == http.StatusTooManyRequests || statusCode >= 500
+		},
+	}
+}
+
+// delay returns how long to wait before the given retry attempt (1-based),
+// honoring the server's Retry-After if it asked for longer than our own
+// backoff would. A random amount up to Jitter is added to the backoff so
+// that many clients retrying the same 429/5xx at once don't all wake up
+// and hammer the API on the same tick.
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	backoff := p.BaseDelay * time.Duration(attempt)
+	if p.Jitter > 0 {
+		backoff += time.Duration(rand.Int63n(int64(p.Jitter)))
+	}
+	if retryAfter > backoff {
+		return retryAfter
+	}
+	return backoff
+}
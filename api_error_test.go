@@ -0,0 +1,85 @@
+package themekit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelayHonorsRetryAfter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 500 * time.Millisecond}
+
+	got := policy.delay(1, 10*time.Second)
+	if got != 10*time.Second {
+		t.Errorf("delay() = %v, want the longer Retry-After of %v", got, 10*time.Second)
+	}
+}
+
+func TestRetryPolicyDelayAppliesJitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 500 * time.Millisecond, Jitter: 250 * time.Millisecond}
+
+	min, max := policy.BaseDelay, policy.BaseDelay+policy.Jitter
+	for i := 0; i < 20; i++ {
+		got := policy.delay(1, 0)
+		if got < min || got >= max {
+			t.Fatalf("delay() = %v, want in [%v, %v)", got, min, max)
+		}
+	}
+}
+
+func TestRetryPolicyDelayScalesWithAttempt(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 500 * time.Millisecond}
+
+	if got := policy.delay(1, 0); got < policy.BaseDelay {
+		t.Errorf("delay(1, 0) = %v, want at least %v", got, policy.BaseDelay)
+	}
+	if got := policy.delay(3, 0); got < 3*policy.BaseDelay {
+		t.Errorf("delay(3, 0) = %v, want at least %v", got, 3*policy.BaseDelay)
+	}
+}
+
+func TestDefaultRetryPolicyShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	cases := map[int]bool{
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+		http.StatusNotFound:            false,
+		http.StatusOK:                  false,
+	}
+	for statusCode, want := range cases {
+		if got := policy.ShouldRetry(statusCode); got != want {
+			t.Errorf("ShouldRetry(%d) = %v, want %v", statusCode, got, want)
+		}
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	if got := retryAfter(resp); got != 2*time.Second {
+		t.Errorf("retryAfter() = %v, want %v", got, 2*time.Second)
+	}
+
+	resp = &http.Response{Header: http.Header{}}
+	if got := retryAfter(resp); got != 0 {
+		t.Errorf("retryAfter() with no header = %v, want 0", got)
+	}
+}
+
+func TestNewAPIErrorParsesFieldEnvelope(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusUnprocessableEntity, Header: http.Header{}}
+	body := []byte(`{"errors":{"value":["can't be blank"]}}`)
+
+	apiErr := NewAPIError(resp, body)
+
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusUnprocessableEntity)
+	}
+	if apiErr.Field != "value" {
+		t.Errorf("Field = %q, want %q", apiErr.Field, "value")
+	}
+	if apiErr.Code != "invalid_asset" {
+		t.Errorf("Code = %q, want %q", apiErr.Code, "invalid_asset")
+	}
+}
@@ -0,0 +1,42 @@
+// Package assets bundles starter themes directly into the themekit binary,
+// so `bootstrap` can work without outbound access to GitHub or any other
+// remote theme source.
+package assets
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed themes
+var themes embed.FS
+
+// Names returns the name of every starter theme bundled with this binary.
+func Names() ([]string, error) {
+	entries, err := themes.ReadDir("themes")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// FS returns the file tree for the bundled starter theme named name, rooted
+// so that paths match the asset keys themekit uploads (e.g.
+// "layout/theme.liquid").
+func FS(name string) (fs.FS, error) {
+	sub, err := fs.Sub(themes, "themes/"+name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fs.Stat(sub, "."); err != nil {
+		return nil, fmt.Errorf("no embedded theme named %q", name)
+	}
+	return sub, nil
+}
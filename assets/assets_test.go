@@ -0,0 +1,58 @@
+package assets
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestNamesIncludesStarter(t *testing.T) {
+	names, err := Names()
+	if err != nil {
+		t.Fatalf("Names() error = %v", err)
+	}
+
+	found := false
+	for _, name := range names {
+		if name == "starter" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Names() = %v, want it to include %q", names, "starter")
+	}
+}
+
+func TestFSUnknownTheme(t *testing.T) {
+	if _, err := FS("does-not-exist"); err == nil {
+		t.Error("FS() for an unbundled theme = nil error, want error")
+	}
+}
+
+// TestFSWalkMatchesAssetKeys guards the contract CreateThemeFromEmbedded
+// relies on: every file under a starter theme's root must be reachable by
+// walking the fs.FS, using paths that are already valid asset keys (no
+// leading "themes/<name>/" prefix).
+func TestFSWalkMatchesAssetKeys(t *testing.T) {
+	themeFS, err := FS("starter")
+	if err != nil {
+		t.Fatalf("FS(%q) error = %v", "starter", err)
+	}
+
+	found := map[string]bool{}
+	err = fs.WalkDir(themeFS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		found[path] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+
+	for _, want := range []string{"layout/theme.liquid", "templates/index.liquid", "config/settings_schema.json"} {
+		if !found[want] {
+			t.Errorf("starter theme walk = %v, want it to include %q", found, want)
+		}
+	}
+}
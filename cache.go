@@ -0,0 +1,336 @@
+package themekit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CacheMode controls how ThemeClient uses its local AssetCache.
+type CacheMode string
+
+const (
+	// CacheOff never reads from or writes to the cache; every call goes
+	// straight to the network, matching the historical behavior.
+	CacheOff CacheMode = "off"
+	// CacheRead serves AssetList/Asset reads from the cache when present,
+	// but does not populate it.
+	CacheRead CacheMode = "read"
+	// CacheReadWrite populates the cache on every successful GET and
+	// updates it on every successful PUT.
+	CacheReadWrite CacheMode = "read-write"
+	// CacheOffline serves reads entirely from the cache and queues
+	// mutations to the pending journal instead of hitting the network.
+	CacheOffline CacheMode = "offline"
+)
+
+// ErrNotCached is returned by ThemeClient.Asset/AssetContext when offline
+// mode can't satisfy a request because nothing has been cached for that key
+// yet.
+type ErrNotCached struct {
+	Key string
+}
+
+func (e ErrNotCached) Error() string {
+	return fmt.Sprintf("asset %q is not cached", e.Key)
+}
+
+// cacheSidecar is the small JSON record that travels alongside each cached
+// asset body, recording enough metadata to decide whether a re-fetch or
+// re-upload is necessary.
+type cacheSidecar struct {
+	Key       string `json:"key"`
+	Hash      string `json:"hash"`
+	ETag      string `json:"etag,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+	Size      int64  `json:"size"`
+}
+
+// AssetCache persists fetched assets under
+// ~/.themekit/cache/<shop>/<theme_id>/, keyed by a content hash, so that
+// dry-run and offline workflows don't need to round-trip every asset
+// through the network on every run.
+type AssetCache struct {
+	mu   sync.Mutex
+	root string
+}
+
+// NewAssetCache builds an AssetCache rooted at
+// ~/.themekit/cache/<shop>/<theme_id>/.
+func NewAssetCache(shop string, themeId int64) (*AssetCache, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Join(home, ".themekit", "cache", shop, fmt.Sprintf("%d", themeId))
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &AssetCache{root: root}, nil
+}
+
+// Hash returns the SHA-256 hex digest of an asset's content.
+func Hash(asset Asset) string {
+	sum := sha256.Sum256(assetContentBytes(asset))
+	return fmt.Sprintf("%x", sum)
+}
+
+func assetContentBytes(asset Asset) []byte {
+	if len(asset.Value) > 0 {
+		return []byte(asset.Value)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(asset.Attachment)
+	if err != nil {
+		return []byte(asset.Attachment)
+	}
+	return decoded
+}
+
+func (c *AssetCache) sidecarPath(key string) string {
+	return filepath.Join(c.root, sanitizeCacheKey(key)+".json")
+}
+
+func (c *AssetCache) bodyPath(hash string) string {
+	return filepath.Join(c.root, "objects", hash)
+}
+
+func sanitizeCacheKey(key string) string {
+	return filepath.FromSlash(key)
+}
+
+// Get returns the cached asset for key, if any.
+func (c *AssetCache) Get(key string) (Asset, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sidecarBytes, err := ioutil.ReadFile(c.sidecarPath(key))
+	if err != nil {
+		return Asset{}, false
+	}
+	var sidecar cacheSidecar
+	if err := json.Unmarshal(sidecarBytes, &sidecar); err != nil {
+		return Asset{}, false
+	}
+	body, err := ioutil.ReadFile(c.bodyPath(sidecar.Hash))
+	if err != nil {
+		return Asset{}, false
+	}
+	return Asset{Key: sidecar.Key, Value: string(body)}, true
+}
+
+// Put persists asset's content and a sidecar recording its hash and etag.
+func (c *AssetCache) Put(asset Asset, etag string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hash := Hash(asset)
+	if err := os.MkdirAll(filepath.Dir(c.bodyPath(hash)), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(c.bodyPath(hash), assetContentBytes(asset), 0644); err != nil {
+		return err
+	}
+
+	sidecar := cacheSidecar{Key: asset.Key, Hash: hash, ETag: etag, Size: int64(len(assetContentBytes(asset)))}
+	encoded, err := json.Marshal(sidecar)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.sidecarPath(asset.Key)), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.sidecarPath(asset.Key), encoded, 0644)
+}
+
+// List returns every asset currently cached, by reading back each sidecar
+// under the cache root. It walks the full tree (not a single-level glob)
+// so keys nested arbitrarily deep, like "templates/customers/order.liquid",
+// are still found.
+func (c *AssetCache) List() []Asset {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	assets := []Asset{}
+	filepath.WalkDir(c.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		if strings.HasPrefix(filepath.Base(path), ".") {
+			return nil
+		}
+
+		sidecarBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var sidecar cacheSidecar
+		if err := json.Unmarshal(sidecarBytes, &sidecar); err != nil {
+			return nil
+		}
+		body, err := ioutil.ReadFile(c.bodyPath(sidecar.Hash))
+		if err != nil {
+			return nil
+		}
+		assets = append(assets, Asset{Key: sidecar.Key, Value: string(body)})
+		return nil
+	})
+	return assets
+}
+
+// Delete removes any cached copy of key.
+func (c *AssetCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := os.Remove(c.sidecarPath(key))
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *AssetCache) journalPath() string {
+	return filepath.Join(c.root, "pending.journal")
+}
+
+// pendingMutation is a single journaled Perform call recorded while offline.
+type pendingMutation struct {
+	Type  EventType `json:"type"`
+	Asset Asset     `json:"asset"`
+}
+
+func (m pendingMutation) AssetEntry() Asset    { return m.Asset }
+func (m pendingMutation) EventKind() EventType { return m.Type }
+
+// QueuePending appends an AssetEvent to the offline journal so it can be
+// replayed later by FlushPending.
+func (c *AssetCache) QueuePending(event AssetEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, err := os.OpenFile(c.journalPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoded, err := json.Marshal(pendingMutation{Type: event.Type(), Asset: event.Asset()})
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(encoded, '\n'))
+	return err
+}
+
+// PendingMutations returns every mutation queued while offline, in the
+// order they were recorded.
+func (c *AssetCache) PendingMutations() ([]pendingMutation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, err := os.Open(c.journalPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mutations := []pendingMutation{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var mutation pendingMutation
+		if err := json.Unmarshal(scanner.Bytes(), &mutation); err != nil {
+			return nil, err
+		}
+		mutations = append(mutations, mutation)
+	}
+	return mutations, scanner.Err()
+}
+
+// ClearPending truncates the offline journal, typically after a
+// successful FlushPending.
+func (c *AssetCache) ClearPending() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := os.Remove(c.journalPath())
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+type queuedAssetEvent struct {
+	asset     Asset
+	eventType EventType
+}
+
+func (e queuedAssetEvent) Asset() Asset    { return e.asset }
+func (e queuedAssetEvent) Type() EventType { return e.eventType }
+
+// FlushPending replays every mutation queued while the client was in
+// CacheOffline mode, returning a channel of the resulting ThemeEvents.
+// It stops early if ctx is cancelled.
+func (t ThemeClient) FlushPending(ctx context.Context) (chan ThemeEvent, error) {
+	results := make(chan ThemeEvent)
+	if t.cache == nil {
+		close(results)
+		return results, nil
+	}
+
+	mutations, err := t.cache.PendingMutations()
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer close(results)
+		var failed []pendingMutation
+		for _, mutation := range mutations {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			event := t.PerformContext(ctx, queuedAssetEvent{asset: mutation.Asset, eventType: mutation.Type})
+			results <- event
+			if !event.Successful() {
+				failed = append(failed, mutation)
+			}
+		}
+
+		// Only the mutations that actually succeeded are done with; a
+		// failure partway through must leave the rest on the journal for
+		// the next flush instead of wiping them along with the successes.
+		t.cache.ClearPending()
+		for _, mutation := range failed {
+			t.cache.QueuePending(queuedAssetEvent{asset: mutation.Asset, eventType: mutation.Type})
+		}
+	}()
+	return results, nil
+}
+
+// Diff returns the subset of local assets whose content hash differs from
+// what's recorded in the cache, so callers can skip uploading assets whose
+// content hasn't actually changed.
+func (t ThemeClient) Diff(local []Asset) []Asset {
+	if t.cache == nil {
+		return local
+	}
+	changed := []Asset{}
+	for _, asset := range local {
+		if cached, ok := t.cache.Get(asset.Key); !ok || Hash(cached) != Hash(asset) {
+			changed = append(changed, asset)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,116 @@
+package themekit
+
+import (
+	"testing"
+)
+
+func newTestCache(t *testing.T) *AssetCache {
+	t.Helper()
+	return &AssetCache{root: t.TempDir()}
+}
+
+func TestAssetCachePutGet(t *testing.T) {
+	cache := newTestCache(t)
+
+	asset := Asset{Key: "templates/index.liquid", Value: "hello"}
+	if err := cache.Put(asset, "etag-1"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	got, ok := cache.Get(asset.Key)
+	if !ok {
+		t.Fatalf("Get(%q) = false, want true", asset.Key)
+	}
+	if got.Value != asset.Value {
+		t.Errorf("Get(%q).Value = %q, want %q", asset.Key, got.Value, asset.Value)
+	}
+}
+
+func TestAssetCacheGetMiss(t *testing.T) {
+	cache := newTestCache(t)
+
+	if _, ok := cache.Get("not/cached.liquid"); ok {
+		t.Errorf("Get() on empty cache = true, want false")
+	}
+}
+
+// TestAssetCacheListNested guards against a past regression where List used
+// filepath.Glob("**/*.json"), which Go treats as a single path segment and
+// so silently dropped sidecars more than one directory deep.
+func TestAssetCacheListNested(t *testing.T) {
+	cache := newTestCache(t)
+
+	assets := []Asset{
+		{Key: "theme.liquid", Value: "top-level"},
+		{Key: "templates/index.liquid", Value: "one-deep"},
+		{Key: "templates/customers/order.liquid", Value: "two-deep"},
+	}
+	for _, asset := range assets {
+		if err := cache.Put(asset, ""); err != nil {
+			t.Fatalf("Put(%q) error = %v", asset.Key, err)
+		}
+	}
+
+	listed := cache.List()
+	if len(listed) != len(assets) {
+		t.Fatalf("List() returned %d assets, want %d: %+v", len(listed), len(assets), listed)
+	}
+
+	byKey := map[string]string{}
+	for _, asset := range listed {
+		byKey[asset.Key] = asset.Value
+	}
+	for _, asset := range assets {
+		if byKey[asset.Key] != asset.Value {
+			t.Errorf("List() missing or wrong value for %q: got %q, want %q", asset.Key, byKey[asset.Key], asset.Value)
+		}
+	}
+}
+
+func TestAssetCacheDelete(t *testing.T) {
+	cache := newTestCache(t)
+
+	asset := Asset{Key: "assets/theme.css", Value: "body{}"}
+	if err := cache.Put(asset, ""); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := cache.Delete(asset.Key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok := cache.Get(asset.Key); ok {
+		t.Errorf("Get() after Delete() = true, want false")
+	}
+
+	// Deleting an already-absent key is not an error.
+	if err := cache.Delete(asset.Key); err != nil {
+		t.Errorf("Delete() on missing key error = %v, want nil", err)
+	}
+}
+
+func TestAssetCachePendingMutations(t *testing.T) {
+	cache := newTestCache(t)
+
+	event := queuedAssetEvent{asset: Asset{Key: "templates/index.liquid", Value: "v2"}, eventType: Update}
+	if err := cache.QueuePending(event); err != nil {
+		t.Fatalf("QueuePending() error = %v", err)
+	}
+
+	pending, err := cache.PendingMutations()
+	if err != nil {
+		t.Fatalf("PendingMutations() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].Asset.Key != event.asset.Key {
+		t.Fatalf("PendingMutations() = %+v, want single mutation for %q", pending, event.asset.Key)
+	}
+
+	if err := cache.ClearPending(); err != nil {
+		t.Fatalf("ClearPending() error = %v", err)
+	}
+	pending, err = cache.PendingMutations()
+	if err != nil {
+		t.Fatalf("PendingMutations() after clear error = %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("PendingMutations() after clear = %+v, want none", pending)
+	}
+}
@@ -1,27 +1,40 @@
 package commands
 
 import (
-	"bytes"
-	"errors"
+	"context"
+	"fmt"
 	"github.com/Shopify/themekit"
-	"net/http"
 	"os"
 )
 
 const (
-	MasterBranch   = "master"
-	LatestRelease  = "latest"
-	ThemeZipRoot   = "https://github.com/Shopify/Timber/archive/"
-	TimberFeedPath = "https://github.com/Shopify/Timber/releases.atom"
+	MasterBranch  = "master"
+	LatestRelease = "latest"
+
+	// DefaultSource is used when the user doesn't pass --source, preserving
+	// the historical Timber-only behavior.
+	DefaultSource = "timber"
 )
 
 type BootstrapOptions struct {
 	BasicOptions
-	Version     string
-	Directory   string
-	Environment string
-	Prefix      string
-	SetThemeId  bool
+	Version      string
+	Directory    string
+	Environment  string
+	Prefix       string
+	SetThemeId   bool
+	Source       string
+	SourceConfig string
+	FromEmbedded string
+	// Theme is the name of the catalog entry to resolve from Source, e.g.
+	// a theme's "name" field in a json-index manifest. It's irrelevant to
+	// sources that only ever host a single theme (timber, github-releases),
+	// so it defaults to Source for those.
+	Theme string
+	// Search, when set, lists catalog entries from Source matching the
+	// query instead of bootstrapping a theme. Only sources that implement
+	// SearchableThemeSource (e.g. json-index) support it.
+	Search string
 }
 
 func BootstrapCommand(args map[string]interface{}) chan bool {
@@ -32,6 +45,11 @@ func BootstrapCommand(args map[string]interface{}) chan bool {
 	extractString(&options.Environment, "environment", args)
 	extractString(&options.Prefix, "prefix", args)
 	extractBool(&options.SetThemeId, "setThemeId", args)
+	extractString(&options.Source, "source", args)
+	extractString(&options.SourceConfig, "source-config", args)
+	extractString(&options.FromEmbedded, "from-embedded", args)
+	extractString(&options.Theme, "theme", args)
+	extractString(&options.Search, "search", args)
 	extractThemeClient(&options.Client, args)
 	extractEventLog(&options.EventLog, args)
 
@@ -53,19 +71,61 @@ func doBootstrap(options BootstrapOptions) chan bool {
 		os.Chdir(options.Directory)
 	}
 
-	zipLocation, err := zipPathForVersion(options.Version)
-	if err != nil {
-		themekit.NotifyError(err)
-		done := make(chan bool)
-		close(done)
-		return done
-	}
+	var clientForNewTheme themekit.ThemeClient
+	var themeEvents chan themekit.ThemeEvent
+
+	if len(options.FromEmbedded) > 0 {
+		name := options.FromEmbedded
+		if len(options.Prefix) > 0 {
+			name = options.Prefix + "-" + name
+		}
+		client, events, err := options.Client.CreateThemeFromEmbedded(context.Background(), name, options.FromEmbedded)
+		if err != nil {
+			themekit.NotifyError(err)
+			done := make(chan bool)
+			close(done)
+			return done
+		}
+		clientForNewTheme, themeEvents = client, events
+	} else {
+		sourceName := options.Source
+		if len(sourceName) == 0 {
+			sourceName = DefaultSource
+		}
+		registerConfiguredSource(sourceName, options.SourceConfig)
+
+		source, err := themekit.GetSource(sourceName)
+		if err != nil {
+			themekit.NotifyError(err)
+			done := make(chan bool)
+			close(done)
+			return done
+		}
 
-	name := "Timber-" + options.Version
-	if len(options.Prefix) > 0 {
-		name = options.Prefix + "-" + name
+		if len(options.Search) > 0 {
+			return searchSource(source, sourceName, options.Search)
+		}
+
+		themeName := options.Theme
+		if len(themeName) == 0 {
+			themeName = sourceName
+		}
+
+		zipLocation, err := source.Resolve(themeName, options.Version)
+		if err != nil {
+			themekit.NotifyError(err)
+			done := make(chan bool)
+			close(done)
+			return done
+		}
+
+		name := themeName + "-" + options.Version
+		if len(options.Prefix) > 0 {
+			name = options.Prefix + "-" + name
+		}
+		clientForNewTheme, themeEvents = options.Client.CreateTheme(name, zipLocation)
 	}
-	clientForNewTheme, themeEvents := options.Client.CreateTheme(name, zipLocation)
+
 	mergeEvents(options.getEventLog(), []chan themekit.ThemeEvent{themeEvents})
 	if options.SetThemeId {
 		AddConfiguration(options.Directory, options.Environment, clientForNewTheme.GetConfiguration())
@@ -82,62 +142,55 @@ func doBootstrap(options BootstrapOptions) chan bool {
 	return done
 }
 
-func zipPath(version string) string {
-	return ThemeZipRoot + version + ".zip"
-}
-
-func zipPathForVersion(version string) (string, error) {
-	if version == MasterBranch {
-		return zipPath(MasterBranch), nil
-	}
-
-	feed, err := downloadAtomFeed()
-	if err != nil {
-		return "", err
-	}
-
-	entry, err := findReleaseWith(feed, version)
-	if err != nil {
-		return "", err
-	}
-
-	return zipPath(entry.Title), nil
-}
+// searchSource prints every catalog entry from src matching query, for
+// sources that support it, and returns a closed done channel since no theme
+// is bootstrapped as a result.
+func searchSource(src themekit.ThemeSource, sourceName, query string) chan bool {
+	done := make(chan bool)
 
-func downloadAtomFeed() (themekit.Feed, error) {
-	resp, err := http.Get(TimberFeedPath)
-	if err != nil {
-		return themekit.Feed{}, err
+	searchable, ok := src.(themekit.SearchableThemeSource)
+	if !ok {
+		themekit.NotifyError(fmt.Errorf("source %q does not support --search", sourceName))
+		close(done)
+		return done
 	}
-	defer resp.Body.Close()
 
-	feed, err := themekit.LoadFeed(resp.Body)
+	descriptors, err := searchable.Search(query)
 	if err != nil {
-		return themekit.Feed{}, err
+		themekit.NotifyError(err)
+		close(done)
+		return done
 	}
-	return feed, nil
-}
 
-func findReleaseWith(feed themekit.Feed, version string) (themekit.Entry, error) {
-	if version == LatestRelease {
-		return feed.LatestEntry(), nil
+	if len(descriptors) == 0 {
+		fmt.Printf("no themes in %q match %q\n", sourceName, query)
 	}
-	for _, entry := range feed.Entries {
-		if entry.Title == version {
-			return entry, nil
+	for _, descriptor := range descriptors {
+		fmt.Printf("%s: %s\n", descriptor.Name, descriptor.Description)
+		for _, version := range descriptor.Versions {
+			fmt.Printf("  - %s\n", version)
 		}
 	}
-	return themekit.Entry{Title: "Invalid Feed"}, buildInvalidVersionError(feed, version)
+	close(done)
+	return done
 }
 
-func buildInvalidVersionError(feed themekit.Feed, version string) error {
-	buff := bytes.NewBuffer([]byte{})
-	buff.Write([]byte(themekit.RedText("Invalid Timber Version: " + version)))
-	buff.Write([]byte("\nAvailable Versions Are:"))
-	buff.Write([]byte("\n  - master"))
-	buff.Write([]byte("\n  - latest"))
-	for _, entry := range feed.Entries {
-		buff.Write([]byte("\n  - " + entry.Title))
+// registerConfiguredSource lazily registers the non-self-registering
+// built-in sources (those that need a --source-config argument) whenever
+// they're requested by name, replacing any earlier registration.
+func registerConfiguredSource(name, config string) {
+	switch name {
+	case "github-releases":
+		if len(config) == 0 {
+			themekit.NotifyError(fmt.Errorf("--source-config=owner/repo is required for the github-releases source"))
+			return
+		}
+		themekit.RegisterSource(name, themekit.NewGithubReleasesSource(config))
+	case "json-index":
+		if len(config) == 0 {
+			themekit.NotifyError(fmt.Errorf("--source-config=<manifest url> is required for the json-index source"))
+			return
+		}
+		themekit.RegisterSource(name, themekit.NewJSONIndexSource(config))
 	}
-	return errors.New(buff.String())
 }
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"github.com/Shopify/themekit"
+)
+
+// DefaultServeAddr binds loopback only. The preview server has no auth on
+// most of its routes, so it shouldn't be reachable from the network unless
+// the caller explicitly passes --addr to widen it.
+const DefaultServeAddr = "127.0.0.1:8080"
+
+type ServeOptions struct {
+	BasicOptions
+	Directory   string
+	Addr        string
+	CertFile    string
+	KeyFile     string
+	UploadToken string
+}
+
+func ServeCommand(args map[string]interface{}) chan bool {
+	options := ServeOptions{}
+
+	extractString(&options.Directory, "directory", args)
+	extractString(&options.Addr, "addr", args)
+	extractString(&options.CertFile, "cert", args)
+	extractString(&options.KeyFile, "key", args)
+	extractString(&options.UploadToken, "upload-token", args)
+	extractThemeClient(&options.Client, args)
+	extractEventLog(&options.EventLog, args)
+
+	return Serve(options)
+}
+
+func Serve(options ServeOptions) chan bool {
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		doServe(options)
+	}()
+	return done
+}
+
+func doServe(options ServeOptions) {
+	addr := options.Addr
+	if len(addr) == 0 {
+		addr = DefaultServeAddr
+	}
+
+	server := themekit.NewPreviewServer(options.Client, options.Directory, addr)
+	server.CertFile = options.CertFile
+	server.KeyFile = options.KeyFile
+	server.UploadToken = options.UploadToken
+
+	if err := server.ListenAndServe(context.Background()); err != nil {
+		themekit.NotifyError(err)
+	}
+}
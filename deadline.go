@@ -0,0 +1,50 @@
+package themekit
+
+import (
+	"sync"
+	"time"
+)
+
+// deadlineTimer is a single mutex-guarded cancel channel per operation,
+// closed exactly once whether it fires on its own timeout or is cancelled
+// explicitly. It lets long-running polling loops (like
+// waitUntilPreviewable) be interrupted cleanly from a signal handler
+// without racing a close on an already-closed channel.
+type deadlineTimer struct {
+	mu     sync.Mutex
+	done   chan struct{}
+	timer  *time.Timer
+	closed bool
+}
+
+// newDeadlineTimer starts a deadlineTimer that fires after d. A zero or
+// negative d means the timer never fires on its own and must be cancelled
+// explicitly.
+func newDeadlineTimer(d time.Duration) *deadlineTimer {
+	dt := &deadlineTimer{done: make(chan struct{})}
+	if d > 0 {
+		dt.timer = time.AfterFunc(d, dt.Cancel)
+	}
+	return dt
+}
+
+// C returns the channel that is closed when the deadline fires or Cancel
+// is called.
+func (dt *deadlineTimer) C() <-chan struct{} {
+	return dt.done
+}
+
+// Cancel stops the underlying timer and closes the done channel. It is
+// safe to call more than once or concurrently.
+func (dt *deadlineTimer) Cancel() {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+	if dt.closed {
+		return
+	}
+	dt.closed = true
+	if dt.timer != nil {
+		dt.timer.Stop()
+	}
+	close(dt.done)
+}
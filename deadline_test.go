@@ -0,0 +1,51 @@
+package themekit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadlineTimerFiresAfterDuration(t *testing.T) {
+	dt := newDeadlineTimer(10 * time.Millisecond)
+
+	select {
+	case <-dt.C():
+	case <-time.After(time.Second):
+		t.Fatal("deadlineTimer did not fire within 1s of a 10ms deadline")
+	}
+}
+
+func TestDeadlineTimerCancel(t *testing.T) {
+	dt := newDeadlineTimer(time.Hour)
+	dt.Cancel()
+
+	select {
+	case <-dt.C():
+	default:
+		t.Fatal("C() was not closed immediately after Cancel()")
+	}
+}
+
+func TestDeadlineTimerCancelIsIdempotent(t *testing.T) {
+	dt := newDeadlineTimer(time.Hour)
+
+	dt.Cancel()
+	dt.Cancel() // must not panic on a double close
+
+	select {
+	case <-dt.C():
+	default:
+		t.Fatal("C() was not closed after Cancel()")
+	}
+}
+
+func TestDeadlineTimerZeroNeverFiresOnItsOwn(t *testing.T) {
+	dt := newDeadlineTimer(0)
+	defer dt.Cancel()
+
+	select {
+	case <-dt.C():
+		t.Fatal("a zero-duration deadlineTimer fired on its own")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
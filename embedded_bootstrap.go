@@ -0,0 +1,85 @@
+package themekit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"github.com/Shopify/themekit/assets"
+)
+
+// CreateThemeFromEmbedded creates a new unpublished theme and populates it
+// from one of the starter themes bundled into the binary via embed.FS,
+// uploading each asset individually through the existing Perform pipeline
+// instead of asking Shopify to fetch a remote zip. This sidesteps the
+// "cannot be retrieved from Github" failure mode entirely for users without
+// outbound GitHub access.
+func (t ThemeClient) CreateThemeFromEmbedded(ctx context.Context, name, embeddedAsset string) (ThemeClient, chan ThemeEvent, error) {
+	themeFS, err := assets.FS(embeddedAsset)
+	if err != nil {
+		return ThemeClient{}, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.createThemeTimeout())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	path := fmt.Sprintf("%s/themes.json", t.config.AdminUrl())
+	contents := map[string]Theme{
+		"theme": Theme{Name: name, Role: "unpublished"},
+	}
+
+	log := make(chan ThemeEvent)
+	logEvent := func(event ThemeEvent) {
+		log <- event
+	}
+
+	data, _ := json.Marshal(contents)
+	themeEvent := t.sendDataContext(ctx, "POST", path, data)
+	go logEvent(themeEvent)
+	if !themeEvent.Successful() {
+		cancel()
+		err := fmt.Errorf("embedded theme %q could not be created", embeddedAsset)
+		NotifyError(err)
+		close(log)
+		return ThemeClient{}, log, err
+	}
+
+	go func() {
+		defer wg.Done()
+		t.waitUntilPreviewable(ctx, themeEvent.ThemeId)
+	}()
+	wg.Wait()
+
+	config := t.GetConfiguration()
+	config.ThemeId = themeEvent.ThemeId
+	newClient := NewThemeClient(config.Initialize())
+
+	go func() {
+		// cancel belongs to this goroutine now: the asset uploads below are
+		// the last thing that reads ctx, so it must stay alive until the
+		// walk finishes rather than being cancelled the moment this
+		// function returns the log channel to its caller.
+		defer cancel()
+		defer close(log)
+		uploadErr := fs.WalkDir(themeFS, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			body, err := fs.ReadFile(themeFS, path)
+			if err != nil {
+				return err
+			}
+			asset := Asset{Key: path, Value: string(body)}
+			logEvent(newClient.PerformContext(ctx, queuedAssetEvent{asset: asset, eventType: Update}))
+			return nil
+		})
+		if uploadErr != nil {
+			NotifyError(uploadErr)
+		}
+	}()
+
+	return newClient, log, nil
+}
@@ -0,0 +1,178 @@
+package themekit
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PreviewServer stands up a small local HTTP server in front of a theme's
+// local working directory, falling back to the remote store for anything
+// not present on disk. It gives designers a live-reload workflow without
+// round-tripping every edit through Shopify.
+type PreviewServer struct {
+	Client    ThemeClient
+	Directory string
+	Addr      string
+	CertFile  string
+	KeyFile   string
+	// UploadToken, if set, must be presented as a "Bearer <token>"
+	// Authorization header on POST /_themekit/upload. Since that endpoint
+	// writes straight to the live remote theme, it's disabled by default
+	// and only opens up once a caller opts in by setting this.
+	UploadToken string
+}
+
+// NewPreviewServer builds a PreviewServer that serves Directory locally,
+// using client as the fallback layer for anything not found on disk.
+func NewPreviewServer(client ThemeClient, directory, addr string) *PreviewServer {
+	return &PreviewServer{Client: client, Directory: directory, Addr: addr}
+}
+
+// ListenAndServe blocks serving the preview server until ctx is cancelled
+// or the server returns a fatal error. If CertFile/KeyFile are set it
+// serves TLS.
+func (s *PreviewServer) ListenAndServe(ctx context.Context) error {
+	server := &http.Server{Addr: s.Addr, Handler: s.Handler()}
+
+	errs := make(chan error, 1)
+	go func() {
+		if len(s.CertFile) > 0 && len(s.KeyFile) > 0 {
+			errs <- server.ListenAndServeTLS(s.CertFile, s.KeyFile)
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return server.Shutdown(context.Background())
+	case err := <-errs:
+		return err
+	}
+}
+
+// Handler builds the PreviewServer's routes: theme asset paths fall back
+// from the local working directory to the remote theme, and a small JSON
+// API under /_themekit lets editors and browser extensions integrate.
+func (s *PreviewServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_themekit/assets", s.handleListAssets)
+	mux.HandleFunc("/_themekit/themes", s.handleListThemes)
+	mux.HandleFunc("/_themekit/upload", s.handleUpload)
+	mux.HandleFunc("/", s.handleAsset)
+	return mux
+}
+
+func (s *PreviewServer) clientForRequest(r *http.Request) ThemeClient {
+	themeIdParam := r.URL.Query().Get("theme")
+	if themeIdParam == "" {
+		return s.Client
+	}
+	themeId, err := strconv.ParseInt(themeIdParam, 10, 64)
+	if err != nil {
+		return s.Client
+	}
+	config := s.Client.GetConfiguration()
+	config.ThemeId = themeId
+	return NewThemeClient(config.Initialize())
+}
+
+func (s *PreviewServer) handleAsset(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if key == "" {
+		key = "templates/index.liquid"
+	}
+
+	if body, err := ioutil.ReadFile(filepath.Join(s.Directory, filepath.FromSlash(key))); err == nil {
+		w.Write(body)
+		return
+	}
+
+	client := s.clientForRequest(r)
+	asset, err := client.AssetContext(r.Context(), key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if client.cache != nil && client.GetConfiguration().CacheMode == CacheReadWrite {
+		client.cache.Put(asset, "")
+	}
+	fmt.Fprint(w, asset.Value)
+}
+
+func (s *PreviewServer) handleListAssets(w http.ResponseWriter, r *http.Request) {
+	client := s.clientForRequest(r)
+	local := client.LocalAssets(s.Directory)
+	remote := client.AssetListSync()
+
+	seen := map[string]bool{}
+	merged := []Asset{}
+	for _, asset := range local {
+		seen[asset.Key] = true
+		merged = append(merged, asset)
+	}
+	for _, asset := range remote {
+		if !seen[asset.Key] {
+			merged = append(merged, asset)
+		}
+	}
+
+	writeJSON(w, map[string][]Asset{"assets": merged})
+}
+
+func (s *PreviewServer) handleListThemes(w http.ResponseWriter, r *http.Request) {
+	client := s.clientForRequest(r)
+	path := fmt.Sprintf("%s/themes.json", client.GetConfiguration().AdminUrl())
+	resp := client.sendDataContext(r.Context(), "GET", path, []byte{})
+	writeJSON(w, resp)
+}
+
+func (s *PreviewServer) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorizedUpload(r) {
+		http.Error(w, "upload disabled: set PreviewServer.UploadToken and pass a matching Bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	var asset Asset
+	if err := json.NewDecoder(r.Body).Decode(&asset); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	client := s.clientForRequest(r)
+	event := client.PerformContext(r.Context(), queuedAssetEvent{asset: asset, eventType: Update})
+	writeJSON(w, event)
+}
+
+// authorizedUpload reports whether r carries the bearer token configured by
+// UploadToken. With no token configured, the write endpoint stays closed.
+func (s *PreviewServer) authorizedUpload(r *http.Request) bool {
+	if len(s.UploadToken) == 0 {
+		return false
+	}
+	header := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(header, "Bearer ")
+	if token == header {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.UploadToken)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
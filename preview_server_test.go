@@ -0,0 +1,42 @@
+package themekit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorizedUploadRequiresConfiguredToken(t *testing.T) {
+	server := &PreviewServer{}
+	req := httptest.NewRequest(http.MethodPost, "/_themekit/upload", nil)
+	req.Header.Set("Authorization", "Bearer anything")
+
+	if server.authorizedUpload(req) {
+		t.Error("authorizedUpload() = true with no UploadToken configured, want false")
+	}
+}
+
+func TestAuthorizedUploadRejectsMissingOrWrongToken(t *testing.T) {
+	server := &PreviewServer{UploadToken: "secret"}
+
+	noAuth := httptest.NewRequest(http.MethodPost, "/_themekit/upload", nil)
+	if server.authorizedUpload(noAuth) {
+		t.Error("authorizedUpload() = true with no Authorization header, want false")
+	}
+
+	wrongToken := httptest.NewRequest(http.MethodPost, "/_themekit/upload", nil)
+	wrongToken.Header.Set("Authorization", "Bearer wrong")
+	if server.authorizedUpload(wrongToken) {
+		t.Error("authorizedUpload() = true with a mismatched token, want false")
+	}
+}
+
+func TestAuthorizedUploadAcceptsMatchingToken(t *testing.T) {
+	server := &PreviewServer{UploadToken: "secret"}
+
+	req := httptest.NewRequest(http.MethodPost, "/_themekit/upload", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	if !server.authorizedUpload(req) {
+		t.Error("authorizedUpload() = false with a matching Bearer token, want true")
+	}
+}
@@ -0,0 +1,77 @@
+package themekit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ThemeDescriptor describes a single theme available from a ThemeSource,
+// independent of where that source actually hosts the zip.
+type ThemeDescriptor struct {
+	Name         string   `json:"name"`
+	Versions     []string `json:"versions,omitempty"`
+	PreviewImage string   `json:"preview_image,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Author       string   `json:"author,omitempty"`
+}
+
+// ThemeSource is the interface bootstrap sources must implement in order to
+// be registered with RegisterSource and used by the `bootstrap` command.
+type ThemeSource interface {
+	// List returns every theme the source knows about.
+	List() ([]ThemeDescriptor, error)
+	// Resolve returns the zip URL for a given theme name and version.
+	Resolve(name, version string) (zipURL string, err error)
+}
+
+// SearchableThemeSource is implemented by sources that can filter their
+// catalog by a free-text query instead of returning everything via List.
+type SearchableThemeSource interface {
+	Search(query string) ([]ThemeDescriptor, error)
+}
+
+var sources = map[string]ThemeSource{}
+
+// RegisterSource makes a ThemeSource available to the bootstrap command
+// under the given name. TimberSource registers itself in init(); sources
+// that need configuration, such as GithubReleasesSource and
+// JSONIndexSource, are registered by their callers.
+func RegisterSource(name string, src ThemeSource) {
+	sources[name] = src
+}
+
+// GetSource looks up a previously registered ThemeSource by name.
+func GetSource(name string) (ThemeSource, error) {
+	src, ok := sources[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme source %q", name)
+	}
+	return src, nil
+}
+
+// SourceNames returns the names of every currently registered ThemeSource,
+// useful for building helpful error messages and --source usage text.
+func SourceNames() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	return names
+}
+
+// buildInvalidVersionError renders a source-aware error listing every theme
+// and version in descriptors, for use when a requested theme/version could
+// not be resolved from the source named sourceName.
+func buildInvalidVersionError(sourceName, version string, descriptors []ThemeDescriptor) error {
+	buff := bytes.NewBuffer([]byte{})
+	buff.WriteString(RedText(fmt.Sprintf("Invalid version %q for source %q", version, sourceName)))
+	buff.WriteString("\nAvailable themes are:")
+	for _, descriptor := range descriptors {
+		buff.WriteString(fmt.Sprintf("\n  - %s", descriptor.Name))
+		for _, v := range descriptor.Versions {
+			buff.WriteString(fmt.Sprintf("\n      - %s", v))
+		}
+	}
+	return errors.New(buff.String())
+}
@@ -0,0 +1,75 @@
+package themekit
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GithubReleasesSource resolves zips from the tags/releases of a single
+// "owner/repo" GitHub repository, the same way TimberSource does for
+// Shopify/Timber, but parameterized so any repo can be used as a bootstrap
+// catalog.
+type GithubReleasesSource struct {
+	OwnerRepo string
+}
+
+// NewGithubReleasesSource builds a GithubReleasesSource for "owner/repo",
+// e.g. "Shopify/Timber".
+func NewGithubReleasesSource(ownerRepo string) GithubReleasesSource {
+	return GithubReleasesSource{OwnerRepo: ownerRepo}
+}
+
+func (s GithubReleasesSource) List() ([]ThemeDescriptor, error) {
+	feed, err := s.downloadAtomFeed()
+	if err != nil {
+		return nil, err
+	}
+	versions := []string{timberMasterBranch, timberLatestRelease}
+	for _, entry := range feed.Entries {
+		versions = append(versions, entry.Title)
+	}
+	return []ThemeDescriptor{
+		{Name: s.OwnerRepo, Versions: versions},
+	}, nil
+}
+
+func (s GithubReleasesSource) Resolve(name, version string) (string, error) {
+	if version == timberMasterBranch {
+		return s.zipPath(timberMasterBranch), nil
+	}
+
+	feed, err := s.downloadAtomFeed()
+	if err != nil {
+		return "", err
+	}
+
+	if version == timberLatestRelease {
+		return s.zipPath(feed.LatestEntry().Title), nil
+	}
+
+	for _, entry := range feed.Entries {
+		if entry.Title == version {
+			return s.zipPath(entry.Title), nil
+		}
+	}
+
+	descriptors, _ := s.List()
+	return "", buildInvalidVersionError(s.OwnerRepo, version, descriptors)
+}
+
+func (s GithubReleasesSource) zipPath(version string) string {
+	return fmt.Sprintf("https://github.com/%s/archive/%s.zip", s.OwnerRepo, version)
+}
+
+func (s GithubReleasesSource) feedPath() string {
+	return fmt.Sprintf("https://github.com/%s/releases.atom", s.OwnerRepo)
+}
+
+func (s GithubReleasesSource) downloadAtomFeed() (Feed, error) {
+	resp, err := http.Get(s.feedPath())
+	if err != nil {
+		return Feed{}, err
+	}
+	defer resp.Body.Close()
+	return LoadFeed(resp.Body)
+}
@@ -0,0 +1,101 @@
+package themekit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// jsonIndexEntry is a single theme entry in a JSONIndexSource manifest.
+type jsonIndexEntry struct {
+	Name         string   `json:"name"`
+	Versions     []string `json:"versions"`
+	Zip          string   `json:"zip"`
+	PreviewImage string   `json:"preview_image,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Author       string   `json:"author,omitempty"`
+}
+
+// JSONIndexSource resolves themes from a self-hosted JSON manifest of the
+// shape: [{"name", "versions", "zip", "preview_image", "description",
+// "author"}, ...]. It's meant for corporate or partner catalogs that don't
+// want to model their releases as GitHub tags.
+type JSONIndexSource struct {
+	ManifestURL string
+}
+
+// NewJSONIndexSource builds a JSONIndexSource backed by the manifest at url.
+func NewJSONIndexSource(url string) JSONIndexSource {
+	return JSONIndexSource{ManifestURL: url}
+}
+
+func (s JSONIndexSource) List() ([]ThemeDescriptor, error) {
+	entries, err := s.fetchManifest()
+	if err != nil {
+		return nil, err
+	}
+	descriptors := make([]ThemeDescriptor, len(entries))
+	for i, entry := range entries {
+		descriptors[i] = ThemeDescriptor{
+			Name:         entry.Name,
+			Versions:     entry.Versions,
+			PreviewImage: entry.PreviewImage,
+			Description:  entry.Description,
+			Author:       entry.Author,
+		}
+	}
+	return descriptors, nil
+}
+
+func (s JSONIndexSource) Search(query string) ([]ThemeDescriptor, error) {
+	descriptors, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	matches := []ThemeDescriptor{}
+	for _, descriptor := range descriptors {
+		if containsFold(descriptor.Name, query) || containsFold(descriptor.Description, query) {
+			matches = append(matches, descriptor)
+		}
+	}
+	return matches, nil
+}
+
+func (s JSONIndexSource) Resolve(name, version string) (string, error) {
+	entries, err := s.fetchManifest()
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.Name != name {
+			continue
+		}
+		for _, v := range entry.Versions {
+			if v == version {
+				return entry.Zip, nil
+			}
+		}
+		descriptors, _ := s.List()
+		return "", buildInvalidVersionError(s.ManifestURL, version, descriptors)
+	}
+	return "", fmt.Errorf("unknown theme %q in index %s", name, s.ManifestURL)
+}
+
+func containsFold(haystack, needle string) bool {
+	return strings.Contains(strings.ToLower(haystack), strings.ToLower(needle))
+}
+
+func (s JSONIndexSource) fetchManifest() ([]jsonIndexEntry, error) {
+	resp, err := http.Get(s.ManifestURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var entries []jsonIndexEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,78 @@
+package themekit
+
+import (
+	"strings"
+	"testing"
+)
+
+type stubSource struct {
+	descriptors []ThemeDescriptor
+	zipURL      string
+}
+
+func (s stubSource) List() ([]ThemeDescriptor, error) { return s.descriptors, nil }
+
+func (s stubSource) Resolve(name, version string) (string, error) {
+	for _, descriptor := range s.descriptors {
+		if descriptor.Name != name {
+			continue
+		}
+		for _, v := range descriptor.Versions {
+			if v == version {
+				return s.zipURL, nil
+			}
+		}
+		return "", buildInvalidVersionError(name, version, s.descriptors)
+	}
+	return "", buildInvalidVersionError(name, version, s.descriptors)
+}
+
+func TestRegisterAndGetSource(t *testing.T) {
+	src := stubSource{zipURL: "https://example.test/theme.zip"}
+	RegisterSource("test-source-get", src)
+
+	got, err := GetSource("test-source-get")
+	if err != nil {
+		t.Fatalf("GetSource() error = %v", err)
+	}
+	gotStub, ok := got.(stubSource)
+	if !ok || gotStub.zipURL != src.zipURL {
+		t.Errorf("GetSource() = %#v, want the registered %#v", got, src)
+	}
+}
+
+func TestGetSourceUnknown(t *testing.T) {
+	if _, err := GetSource("does-not-exist"); err == nil {
+		t.Errorf("GetSource() on unregistered name = nil error, want error")
+	}
+}
+
+func TestSourceNamesIncludesRegistered(t *testing.T) {
+	RegisterSource("test-source-names", stubSource{})
+
+	names := SourceNames()
+	found := false
+	for _, name := range names {
+		if name == "test-source-names" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("SourceNames() = %v, want it to include %q", names, "test-source-names")
+	}
+}
+
+func TestBuildInvalidVersionErrorDoesNotInterpretPercent(t *testing.T) {
+	descriptors := []ThemeDescriptor{
+		{Name: "100%-theme", Versions: []string{"1.0"}},
+	}
+
+	err := buildInvalidVersionError("100%-theme", "2.0", descriptors)
+
+	if !strings.Contains(err.Error(), "100%-theme") {
+		t.Errorf("error message = %q, want it to contain the literal theme name %q", err.Error(), "100%-theme")
+	}
+	if strings.Contains(err.Error(), "%!") {
+		t.Errorf("error message = %q, looks like an unintended printf verb leaked through", err.Error())
+	}
+}
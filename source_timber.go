@@ -0,0 +1,85 @@
+package themekit
+
+import "net/http"
+
+const (
+	timberMasterBranch  = "master"
+	timberLatestRelease = "latest"
+	timberZipRoot       = "https://github.com/Shopify/Timber/archive/"
+	timberFeedPath      = "https://github.com/Shopify/Timber/releases.atom"
+)
+
+// TimberSource is the original, hard-coded bootstrap source: it resolves
+// versions against the Shopify/Timber Atom releases feed.
+type TimberSource struct{}
+
+func init() {
+	RegisterSource("timber", TimberSource{})
+}
+
+func (s TimberSource) List() ([]ThemeDescriptor, error) {
+	feed, err := s.downloadAtomFeed()
+	if err != nil {
+		return nil, err
+	}
+	versions := []string{timberMasterBranch, timberLatestRelease}
+	for _, entry := range feed.Entries {
+		versions = append(versions, entry.Title)
+	}
+	return []ThemeDescriptor{
+		{Name: "Timber", Versions: versions, Description: "Shopify's reference theme"},
+	}, nil
+}
+
+func (s TimberSource) Resolve(name, version string) (string, error) {
+	if version == timberMasterBranch {
+		return s.zipPath(timberMasterBranch), nil
+	}
+
+	feed, err := s.downloadAtomFeed()
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := s.findReleaseWith(feed, version)
+	if err != nil {
+		return "", err
+	}
+
+	return s.zipPath(entry.Title), nil
+}
+
+func (s TimberSource) zipPath(version string) string {
+	return timberZipRoot + version + ".zip"
+}
+
+func (s TimberSource) downloadAtomFeed() (Feed, error) {
+	resp, err := http.Get(timberFeedPath)
+	if err != nil {
+		return Feed{}, err
+	}
+	defer resp.Body.Close()
+
+	feed, err := LoadFeed(resp.Body)
+	if err != nil {
+		return Feed{}, err
+	}
+	return feed, nil
+}
+
+func (s TimberSource) findReleaseWith(feed Feed, version string) (Entry, error) {
+	if version == timberLatestRelease {
+		return feed.LatestEntry(), nil
+	}
+	for _, entry := range feed.Entries {
+		if entry.Title == version {
+			return entry, nil
+		}
+	}
+	return Entry{Title: "Invalid Feed"}, buildInvalidTimberVersionError(feed, version)
+}
+
+func buildInvalidTimberVersionError(feed Feed, version string) error {
+	descriptors, _ := TimberSource{}.List()
+	return buildInvalidVersionError("timber", version, descriptors)
+}
@@ -2,10 +2,12 @@ package themekit
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -18,10 +20,20 @@ import (
 
 const CreateThemeMaxRetries int = 3
 
+// DefaultRequestTimeout is used for AssetList/Asset/Perform calls when
+// Configuration.RequestTimeout is unset.
+const DefaultRequestTimeout = 30 * time.Second
+
+// DefaultCreateThemeTimeout bounds how long CreateTheme will poll for the
+// new theme to become previewable when Configuration.CreateThemeTimeout is
+// unset.
+const DefaultCreateThemeTimeout = 5 * time.Minute
+
 type ThemeClient struct {
 	config Configuration
 	client *http.Client
 	filter EventFilter
+	cache  *AssetCache
 }
 
 type Theme struct {
@@ -33,9 +45,12 @@ type Theme struct {
 }
 
 type apiResponse struct {
-	code int
-	body []byte
-	err  error
+	code           int
+	body           []byte
+	etag           string
+	err            error
+	apiError       *APIError
+	shopLimitDelay time.Duration
 }
 
 type EventType int
@@ -72,11 +87,17 @@ type AssetEvent interface {
 }
 
 func NewThemeClient(config Configuration) ThemeClient {
-	return ThemeClient{
+	client := ThemeClient{
 		config: config,
 		client: newHttpClient(config),
 		filter: NewEventFilterFromPatternsAndFiles(config.IgnoredFiles, config.Ignores),
 	}
+	if config.CacheMode != "" && config.CacheMode != CacheOff {
+		if cache, err := NewAssetCache(sanitizeCacheKey(config.AdminUrl()), config.ThemeId); err == nil {
+			client.cache = cache
+		}
+	}
+	return client
 }
 
 func (t ThemeClient) GetConfiguration() Configuration {
@@ -87,17 +108,51 @@ func (t ThemeClient) LeakyBucket() *LeakyBucket {
 	return NewLeakyBucket(t.config.BucketSize, t.config.RefillRate, 1)
 }
 
+// AssetList is a thin wrapper around AssetListContext using a background
+// context, kept for callers that don't need cancellation.
 func (t ThemeClient) AssetList() (results chan Asset, errs chan error) {
+	return t.AssetListContext(context.Background())
+}
+
+func (t ThemeClient) AssetListContext(ctx context.Context) (results chan Asset, errs chan error) {
 	results = make(chan Asset)
 	errs = make(chan error)
 	go func() {
+		defer close(results)
+		defer close(errs)
+
+		if t.config.CacheMode == CacheOffline && t.cache != nil {
+			for _, asset := range t.cache.List() {
+				select {
+				case results <- asset:
+				case <-ctx.Done():
+					return
+				}
+			}
+			return
+		}
+
+		if t.config.CacheMode == CacheRead && t.cache != nil {
+			if cached := t.cache.List(); len(cached) > 0 {
+				for _, asset := range cached {
+					select {
+					case results <- asset:
+					case <-ctx.Done():
+						return
+					}
+				}
+				return
+			}
+		}
+
 		queryBuilder := func(path string) string {
 			return path
 		}
 
-		resp := t.query(queryBuilder)
+		resp := t.queryContext(ctx, queryBuilder)
 		if resp.err != nil {
 			errs <- resp.err
+			return
 		}
 
 		var assets map[string][]Asset
@@ -110,11 +165,19 @@ func (t ThemeClient) AssetList() (results chan Asset, errs chan error) {
 		sort.Sort(ByAsset(assets["assets"]))
 		sanitizedAssets := ignoreCompiledAssets(assets["assets"])
 
+		if t.cache != nil && (t.config.CacheMode == CacheReadWrite) {
+			for _, asset := range sanitizedAssets {
+				t.cache.Put(asset, "")
+			}
+		}
+
 		for _, asset := range sanitizedAssets {
-			results <- asset
+			select {
+			case results <- asset:
+			case <-ctx.Done():
+				return
+			}
 		}
-		close(results)
-		close(errs)
 	}()
 	return
 }
@@ -151,28 +214,101 @@ func (t ThemeClient) LocalAssets(dir string) []Asset {
 
 type AssetRetrieval func(filename string) (Asset, error)
 
+// Asset is a thin wrapper around AssetContext using a background context.
 func (t ThemeClient) Asset(filename string) (Asset, error) {
+	return t.AssetContext(context.Background(), filename)
+}
+
+func (t ThemeClient) AssetContext(ctx context.Context, filename string) (Asset, error) {
+	if t.config.CacheMode == CacheOffline && t.cache != nil {
+		if asset, ok := t.cache.Get(filename); ok {
+			return asset, nil
+		}
+		return Asset{}, ErrNotCached{Key: filename}
+	}
+
+	if t.config.CacheMode == CacheRead && t.cache != nil {
+		if asset, ok := t.cache.Get(filename); ok {
+			return asset, nil
+		}
+		// fall through to the network; CacheRead never populates the cache
+	}
+
 	queryBuilder := func(path string) string {
 		return fmt.Sprintf("%s&asset[key]=%s", path, filename)
 	}
 
-	resp := t.query(queryBuilder)
-	if resp.err != nil {
-		return Asset{}, resp.err
-	}
-	if resp.code >= 400 {
-		return Asset{}, NonFatalNetworkError{Code: resp.code, Verb: "GET", Message: "not found"}
+	policy := DefaultRetryPolicy()
+	var resp apiResponse
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		resp = t.queryContext(ctx, queryBuilder)
+		if resp.err != nil {
+			return Asset{}, resp.err
+		}
+		if resp.code < 400 {
+			break
+		}
+		if attempt == policy.MaxAttempts || !policy.ShouldRetry(resp.code) {
+			if resp.apiError != nil {
+				return Asset{}, *resp.apiError
+			}
+			return Asset{}, NonFatalNetworkError{Code: resp.code, Verb: "GET", Message: "not found"}
+		}
+		delay := policy.delay(attempt, retryAfterOf(resp.apiError))
+		if resp.shopLimitDelay > delay {
+			delay = resp.shopLimitDelay
+		}
+		if !sleepContext(ctx, delay) {
+			return Asset{}, ctx.Err()
+		}
 	}
+
 	var asset map[string]Asset
 	err := json.Unmarshal(resp.body, &asset)
 	if err != nil {
 		return Asset{}, err
 	}
 
+	if t.cache != nil && t.config.CacheMode == CacheReadWrite {
+		t.cache.Put(asset["asset"], resp.etag)
+	}
+
 	return asset["asset"], nil
 }
 
+func retryAfterOf(apiErr *APIError) time.Duration {
+	if apiErr == nil {
+		return 0
+	}
+	return apiErr.RetryAfter
+}
+
+// sleepContext waits for d or until ctx is cancelled, returning false in
+// the latter case.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := newDeadlineTimer(d)
+	select {
+	case <-ctx.Done():
+		timer.Cancel()
+		return false
+	case <-timer.C():
+		return true
+	}
+}
+
+// CreateTheme is a thin wrapper around CreateThemeContext using a
+// background context.
 func (t ThemeClient) CreateTheme(name, zipLocation string) (ThemeClient, chan ThemeEvent) {
+	return t.CreateThemeContext(context.Background(), name, zipLocation)
+}
+
+func (t ThemeClient) CreateThemeContext(ctx context.Context, name, zipLocation string) (ThemeClient, chan ThemeEvent) {
+	ctx, cancel := context.WithTimeout(ctx, t.createThemeTimeout())
+	defer cancel()
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 	path := fmt.Sprintf("%s/themes.json", t.config.AdminUrl())
@@ -185,19 +321,23 @@ func (t ThemeClient) CreateTheme(name, zipLocation string) (ThemeClient, chan Th
 		log <- t
 	}
 
-	retries := 0
+	policy := DefaultRetryPolicy()
+	attempts := 0
 	themeEvent := func() (themeEvent APIThemeEvent) {
 		ready := false
 		data, _ := json.Marshal(contents)
-		for retries < CreateThemeMaxRetries && !ready {
-			if themeEvent = t.sendData("POST", path, data); !themeEvent.Successful() {
-				retries++
+		for attempts < policy.MaxAttempts && !ready {
+			attempts++
+			if themeEvent = t.sendDataContext(ctx, "POST", path, data); !themeEvent.Successful() {
+				if attempts < policy.MaxAttempts {
+					sleepContext(ctx, policy.delay(attempts, 0))
+				}
 			} else {
 				ready = true
 			}
 			go logEvent(themeEvent)
 		}
-		if retries >= CreateThemeMaxRetries {
+		if !ready {
 			err := errors.New(fmt.Sprintf("'%s' cannot be retrieved from Github.", zipLocation))
 			NotifyError(err)
 		}
@@ -205,10 +345,8 @@ func (t ThemeClient) CreateTheme(name, zipLocation string) (ThemeClient, chan Th
 	}()
 
 	go func() {
-		for !t.isDoneProcessing(themeEvent.ThemeId) {
-			time.Sleep(250 * time.Millisecond)
-		}
-		wg.Done()
+		defer wg.Done()
+		t.waitUntilPreviewable(ctx, themeEvent.ThemeId)
 	}()
 
 	wg.Wait()
@@ -217,28 +355,71 @@ func (t ThemeClient) CreateTheme(name, zipLocation string) (ThemeClient, chan Th
 	return NewThemeClient(config.Initialize()), log
 }
 
+func (t ThemeClient) requestTimeout() time.Duration {
+	if t.config.RequestTimeout > 0 {
+		return t.config.RequestTimeout
+	}
+	return DefaultRequestTimeout
+}
+
+func (t ThemeClient) createThemeTimeout() time.Duration {
+	if t.config.CreateThemeTimeout > 0 {
+		return t.config.CreateThemeTimeout
+	}
+	return DefaultCreateThemeTimeout
+}
+
+// Process is a thin wrapper around ProcessContext using a background
+// context.
 func (t ThemeClient) Process(events chan AssetEvent) (done chan bool, messages chan ThemeEvent) {
+	return t.ProcessContext(context.Background(), events)
+}
+
+func (t ThemeClient) ProcessContext(ctx context.Context, events chan AssetEvent) (done chan bool, messages chan ThemeEvent) {
 	done = make(chan bool)
 	messages = make(chan ThemeEvent)
 	go func() {
+		defer close(messages)
 		for {
-			job, more := <-events
-			if more {
-				messages <- t.Perform(job)
-			} else {
-				close(messages)
+			select {
+			case <-ctx.Done():
 				done <- true
 				return
+			case job, more := <-events:
+				if !more {
+					done <- true
+					return
+				}
+				messages <- t.PerformContext(ctx, job)
 			}
 		}
 	}()
 	return
 }
 
+// Perform is a thin wrapper around PerformContext using a background
+// context.
 func (t ThemeClient) Perform(asset AssetEvent) ThemeEvent {
+	return t.PerformContext(context.Background(), asset)
+}
+
+func (t ThemeClient) PerformContext(ctx context.Context, asset AssetEvent) ThemeEvent {
 	if t.filter.MatchesFilter(asset.Asset().Key) {
 		return NoOpEvent{}
 	}
+
+	if t.config.CacheMode == CacheOffline && t.cache != nil {
+		if err := t.cache.QueuePending(asset); err != nil {
+			return processResponse(nil, err, asset)
+		}
+		if asset.Type() == Remove {
+			t.cache.Delete(asset.Asset().Key)
+		} else {
+			t.cache.Put(asset.Asset(), "")
+		}
+		return NoOpEvent{}
+	}
+
 	var event string
 	switch asset.Type() {
 	case Update:
@@ -246,18 +427,71 @@ func (t ThemeClient) Perform(asset AssetEvent) ThemeEvent {
 	case Remove:
 		event = "DELETE"
 	}
-	resp, err := t.request(asset, event)
+
+	policy := DefaultRetryPolicy()
+	var resp *http.Response
+	var err error
+	var apiErr *APIError
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		resp, err = t.requestContext(ctx, asset, event)
+		if err != nil {
+			break
+		}
+		if resp.StatusCode < 400 {
+			apiErr = nil
+			break
+		}
+
+		// Read the body once here so apiErr can be built for every failing
+		// response, including the one that ends the loop, then hand
+		// processResponse a fresh reader over the same bytes so it can
+		// still parse the event out of the body as before.
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		parsed := NewAPIError(resp, body)
+		apiErr = &parsed
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		if attempt == policy.MaxAttempts || !policy.ShouldRetry(resp.StatusCode) {
+			break
+		}
+		delay := policy.delay(attempt, apiErr.RetryAfter)
+		if shopDelay := shopAPICallLimitDelay(resp, t.config.RefillRate); shopDelay > delay {
+			delay = shopDelay
+		}
+		if !sleepContext(ctx, delay) {
+			break
+		}
+	}
 	if err == nil {
 		defer resp.Body.Close()
 	}
-	return processResponse(resp, err, asset)
+	themeEvent := processResponse(resp, err, asset)
+	if apiErr != nil {
+		themeEvent = apiErrorEvent{ThemeEvent: themeEvent, apiErr: *apiErr}
+	}
+	if t.cache != nil && t.config.CacheMode == CacheReadWrite && resp != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if asset.Type() == Remove {
+			t.cache.Delete(asset.Asset().Key)
+		} else {
+			t.cache.Put(asset.Asset(), resp.Header.Get("ETag"))
+		}
+	}
+	return themeEvent
 }
 
 func (t ThemeClient) query(queryBuilder func(path string) string) apiResponse {
+	return t.queryContext(context.Background(), queryBuilder)
+}
+
+func (t ThemeClient) queryContext(ctx context.Context, queryBuilder func(path string) string) apiResponse {
+	ctx, cancel := context.WithTimeout(ctx, t.requestTimeout())
+	defer cancel()
+
 	path := fmt.Sprintf("%s?fields=key,attachment,value", t.config.AssetPath())
 	path = queryBuilder(path)
 
-	req, err := http.NewRequest("GET", path, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", path, nil)
 	if err != nil {
 		return apiResponse{err: err}
 	}
@@ -270,11 +504,29 @@ func (t ThemeClient) query(queryBuilder func(path string) string) apiResponse {
 		defer resp.Body.Close()
 	}
 	body, err := ioutil.ReadAll(resp.Body)
-	return apiResponse{code: resp.StatusCode, body: body, err: err}
+	result := apiResponse{
+		code:           resp.StatusCode,
+		body:           body,
+		etag:           resp.Header.Get("ETag"),
+		err:            err,
+		shopLimitDelay: shopAPICallLimitDelay(resp, t.config.RefillRate),
+	}
+	if resp.StatusCode >= 400 {
+		apiErr := NewAPIError(resp, body)
+		result.apiError = &apiErr
+	}
+	return result
 }
 
 func (t ThemeClient) sendData(method, path string, body []byte) (result APIThemeEvent) {
-	req, err := http.NewRequest(method, path, bytes.NewBuffer(body))
+	return t.sendDataContext(context.Background(), method, path, body)
+}
+
+func (t ThemeClient) sendDataContext(ctx context.Context, method, path string, body []byte) (result APIThemeEvent) {
+	ctx, cancel := context.WithTimeout(ctx, t.requestTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, path, bytes.NewBuffer(body))
 	if err != nil {
 		NotifyError(err)
 	}
@@ -287,28 +539,87 @@ func (t ThemeClient) sendData(method, path string, body []byte) (result APITheme
 }
 
 func (t ThemeClient) request(event AssetEvent, method string) (*http.Response, error) {
+	return t.requestContext(context.Background(), event, method)
+}
+
+func (t ThemeClient) requestContext(ctx context.Context, event AssetEvent, method string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.requestTimeout())
+
 	path := t.config.AssetPath()
 	data := map[string]Asset{"asset": event.Asset()}
 	encoded, err := json.Marshal(data)
 
-	req, err := http.NewRequest(method, path, bytes.NewBuffer(encoded))
-
+	req, err := http.NewRequestWithContext(ctx, method, path, bytes.NewBuffer(encoded))
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
 	t.config.AddHeaders(req)
-	return t.client.Do(req)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The request's context must stay alive until the caller is done
+	// reading resp.Body, not just until Do returns — otherwise the
+	// deferred cancel in a naive implementation races the body read and
+	// can surface as "context canceled" on anything but a trivially
+	// small response. Tie cancellation to Body.Close() instead.
+	resp.Body = cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody defers releasing a request's context until its body is
+// closed, so the context outlives every caller's read of resp.Body.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
 }
 
 func processResponse(r *http.Response, err error, event AssetEvent) ThemeEvent {
 	return NewAPIAssetEvent(r, event, err)
 }
 
-func (t ThemeClient) isDoneProcessing(themeId int64) bool {
+// apiErrorEvent decorates a ThemeEvent with the APIError that produced it,
+// so that errors.As(themeEvent, &themekit.APIError{}) works against what
+// Perform returns the same way it already does for the error Asset returns.
+type apiErrorEvent struct {
+	ThemeEvent
+	apiErr APIError
+}
+
+func (e apiErrorEvent) Unwrap() error {
+	return e.apiErr
+}
+
+// waitUntilPreviewable polls the theme's status until it becomes
+// previewable, or ctx is cancelled/deadlined. It uses a deadlineTimer to
+// drive each poll tick so the loop can be interrupted instantly rather
+// than waiting out a sleep.
+func (t ThemeClient) waitUntilPreviewable(ctx context.Context, themeId int64) bool {
 	path := fmt.Sprintf("%s/themes/%d.json", t.config.AdminUrl(), themeId)
-	themeEvent := t.sendData("GET", path, []byte{})
-	return themeEvent.Previewable
+	for {
+		themeEvent := t.sendDataContext(ctx, "GET", path, []byte{})
+		if themeEvent.Previewable {
+			return true
+		}
+
+		tick := newDeadlineTimer(250 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			tick.Cancel()
+			return false
+		case <-tick.C():
+		}
+	}
 }
 
 func ExtractErrorMessage(data []byte, err error) string {